@@ -0,0 +1,142 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// BeginTx starts the transaction, propagating ctx and opts to the driver so
+// that cancellation and deadlines reach it. A nil opts behaves like Begin.
+func (wrapper *Wrapper) BeginTx(ctx context.Context, opts *sql.TxOptions) (err error) {
+	ctx = wrapper.beforeBegin(ctx)
+	wrapper.Transaction, err = wrapper.Connection.BeginTx(ctx, opts)
+	wrapper.afterBegin(ctx, err)
+	return
+}
+
+// CommitContext is the context-aware equivalent of Commit. database/sql's
+// *sql.Tx has no context-aware commit of its own, so this only honours
+// cancellation/deadlines before delegating to Commit.
+func (wrapper *Wrapper) CommitContext(ctx context.Context) (err error) {
+	ctx = wrapper.beforeCommit(ctx)
+	defer func() { wrapper.afterCommit(ctx, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	if err = wrapper.Transaction.Commit(); err != nil {
+		return
+	}
+	wrapper.Transaction = nil
+	return
+}
+
+// PrepareContext is the context-aware equivalent of Prepare.
+func (wrapper *Wrapper) PrepareContext(ctx context.Context, statement string) (*sql.Stmt, error) {
+	if wrapper.Transaction != nil {
+		return wrapper.Transaction.PrepareContext(ctx, statement)
+	}
+	return wrapper.Connection.PrepareContext(ctx, statement)
+}
+
+// ExecuteContext is the context-aware equivalent of Execute.
+func (wrapper *Wrapper) ExecuteContext(ctx context.Context, statementString string, params ...interface{}) (err error) {
+	ctx = wrapper.beforeExecute(ctx, statementString, params)
+	var rowsAffected int64
+	defer func() { wrapper.afterExecute(ctx, statementString, params, rowsAffected, err) }()
+
+	statement, err := wrapper.PrepareContext(ctx, statementString)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	results, err := statement.ExecContext(ctx, params...)
+	if err != nil {
+		return err
+	}
+
+	wrapper.lastResult = results
+	rowsAffected, _ = results.RowsAffected()
+
+	return nil
+}
+
+//QueryContext is the context-aware equivalent of Query.
+func (wrapper *Wrapper) QueryContext(ctx context.Context, queryStatement string, params ...interface{}) (err error) {
+	wrapper.clear()
+	ctx = wrapper.beforeQuery(ctx, queryStatement, params)
+	defer func() { wrapper.afterQuery(ctx, queryStatement, params, int64(len(wrapper.ScanResults)), err) }()
+
+	cursor, err := wrapper.QueryStreamContext(ctx, queryStatement, params...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		wrapper.ScanResults = append(wrapper.ScanResults, cursor.current)
+	}
+
+	err = cursor.Err()
+	return err
+}
+
+//QueryOneContext is the context-aware equivalent of QueryOne.
+func (wrapper *Wrapper) QueryOneContext(ctx context.Context, queryStatement string, params ...interface{}) (i interface{}, err error) {
+	wrapper.clear()
+	ctx = wrapper.beforeQuery(ctx, queryStatement, params)
+	defer func() {
+		var rowsAffected int64
+		if err == nil {
+			rowsAffected = 1
+		}
+		wrapper.afterQuery(ctx, queryStatement, params, rowsAffected, err)
+	}()
+
+	statement, err := wrapper.PrepareContext(ctx, queryStatement)
+	if err != nil {
+		return
+	}
+	defer statement.Close()
+
+	results, err := statement.QueryContext(ctx, params...)
+	if err != nil {
+		return
+	}
+	defer statement.Close()
+
+	var fArr []string
+	if fArr, err = results.Columns(); err != nil || !results.Next() {
+		return
+	}
+	if len(fArr) > 1 {
+		err = errors.New("May only return one value with this function")
+		return
+	}
+
+	var sr ScanResult
+	sr.PutFields(fArr)
+	if err = results.Scan(sr.GetFieldPtrArr()...); err != nil {
+		return
+	}
+
+	i = sr.GetInterface(fArr[0])
+
+	return
+}
@@ -0,0 +1,141 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+// typeDecoder converts a raw driver value into a Go value assignable to the
+// reflect.Type it is registered under.
+type typeDecoder func(driverValue interface{}) (interface{}, error)
+
+var (
+	decoderMu    sync.RWMutex
+	typeDecoders = map[reflect.Type]typeDecoder{}
+)
+
+// RegisterTypeDecoder registers decode as the function used to populate any
+// struct field of type target during Wrapper.Unmarshal/Wrapper.Unwrap,
+// overriding any previously registered decoder for that type (including the
+// built-in *time.Time, *geo.Point, and *geo.Path decoders). This lets callers
+// teach the wrapper about types it does not know about natively, such as
+// decimal.Decimal or geometry types from a different geo library, without
+// patching the module. Decoders are only consulted for fields whose kind is
+// struct or pointer; a field of any other kind (an array type such as a
+// [16]byte UUID, a string, an int) keeps the built-in handling and its
+// registered decoder is not called.
+func RegisterTypeDecoder(target reflect.Type, decode func(driverValue interface{}) (interface{}, error)) {
+	decoderMu.Lock()
+	typeDecoders[target] = decode
+	decoderMu.Unlock()
+
+	mapperMu.Lock()
+	typeMappers = make(map[reflect.Type]*structMapper)
+	mapperMu.Unlock()
+}
+
+func lookupTypeDecoder(t reflect.Type) (typeDecoder, bool) {
+	decoderMu.RLock()
+	defer decoderMu.RUnlock()
+	d, ok := typeDecoders[t]
+	return d, ok
+}
+
+// DecodeError is returned when a registered type decoder fails to convert a
+// driver value for the named column.
+type DecodeError struct {
+	Column string
+	Type   reflect.Type
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("godb: decode column %q into %s: %v", e.Column, e.Type, e.Err)
+}
+
+// Unwrap allows errors.Is/errors.As to reach the underlying decode error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func init() {
+	RegisterTypeDecoder(timePType, decodeTime)
+	RegisterTypeDecoder(geoPoint, decodeGeoPoint)
+	RegisterTypeDecoder(geoPath, decodeGeoPath)
+}
+
+func decodeTime(driverValue interface{}) (interface{}, error) {
+	var s string
+	switch value := driverValue.(type) {
+	case nil:
+		return &time.Time{}, nil
+	case string:
+		s = value
+	case []uint8:
+		s = string(value)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", driverValue)
+	}
+
+	t := stringToTimePtr(s)
+	if t == nil {
+		return nil, fmt.Errorf("unrecognized time value %q", s)
+	}
+	return t, nil
+}
+
+func decodeGeoPoint(driverValue interface{}) (interface{}, error) {
+	if driverValue == nil {
+		return &geo.Point{}, nil
+	}
+	byts, err := wkbBytes(driverValue)
+	if err != nil {
+		return nil, err
+	}
+	return geo.NewPointFromWKB(byts), nil
+}
+
+func decodeGeoPath(driverValue interface{}) (interface{}, error) {
+	if driverValue == nil {
+		return &geo.Path{}, nil
+	}
+	byts, err := wkbBytes(driverValue)
+	if err != nil {
+		return nil, err
+	}
+	return geo.NewPathFromWKB(byts), nil
+}
+
+// wkbBytes strips the 4-byte SRID prefix that MySQL/PostGIS put in front of a
+// geometry column's WKB value before go.geo can parse it.
+func wkbBytes(driverValue interface{}) ([]byte, error) {
+	raw, ok := driverValue.([]uint8)
+	if !ok {
+		return nil, fmt.Errorf("expected []uint8, got %T", driverValue)
+	}
+	if len(raw) <= 4 {
+		return nil, fmt.Errorf("WKB value too short (%d bytes)", len(raw))
+	}
+
+	byts := make([]byte, len(raw)-4)
+	copy(byts, raw[4:])
+	return byts, nil
+}
@@ -0,0 +1,208 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"time"
+)
+
+// Hook lets callers observe every query, exec, and transaction boundary a
+// Wrapper runs, without editing the wrapper itself — structured logging,
+// OpenTelemetry spans, Prometheus counters, and the like. Register one with
+// Wrapper.AddHook. Queries run through QueryStream, QueryStreamContext, or
+// StreamUnwrap are not reported to hooks.
+//
+// Before* hooks may return a derived context (for example one with a span
+// attached); that context is threaded through to the matching After* hook
+// and, for BeforeQuery/BeforeExecute/BeforeBegin, on into the database/sql
+// call itself.
+type Hook interface {
+	BeforeQuery(ctx context.Context, sql string, args []interface{}) context.Context
+	AfterQuery(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error)
+
+	BeforeExecute(ctx context.Context, sql string, args []interface{}) context.Context
+	AfterExecute(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error)
+
+	BeforeBegin(ctx context.Context) context.Context
+	AfterBegin(ctx context.Context, err error)
+
+	BeforeCommit(ctx context.Context) context.Context
+	AfterCommit(ctx context.Context, err error)
+
+	BeforeRevert(ctx context.Context) context.Context
+	AfterRevert(ctx context.Context, err error)
+}
+
+func (wrapper *Wrapper) beforeQuery(ctx context.Context, sql string, args []interface{}) context.Context {
+	for _, hook := range wrapper.hooks {
+		ctx = hook.BeforeQuery(ctx, sql, args)
+	}
+	return ctx
+}
+
+func (wrapper *Wrapper) afterQuery(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error) {
+	for _, hook := range wrapper.hooks {
+		hook.AfterQuery(ctx, sql, args, rowsAffected, err)
+	}
+}
+
+func (wrapper *Wrapper) beforeExecute(ctx context.Context, sql string, args []interface{}) context.Context {
+	for _, hook := range wrapper.hooks {
+		ctx = hook.BeforeExecute(ctx, sql, args)
+	}
+	return ctx
+}
+
+func (wrapper *Wrapper) afterExecute(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error) {
+	for _, hook := range wrapper.hooks {
+		hook.AfterExecute(ctx, sql, args, rowsAffected, err)
+	}
+}
+
+func (wrapper *Wrapper) beforeBegin(ctx context.Context) context.Context {
+	for _, hook := range wrapper.hooks {
+		ctx = hook.BeforeBegin(ctx)
+	}
+	return ctx
+}
+
+func (wrapper *Wrapper) afterBegin(ctx context.Context, err error) {
+	for _, hook := range wrapper.hooks {
+		hook.AfterBegin(ctx, err)
+	}
+}
+
+func (wrapper *Wrapper) beforeCommit(ctx context.Context) context.Context {
+	for _, hook := range wrapper.hooks {
+		ctx = hook.BeforeCommit(ctx)
+	}
+	return ctx
+}
+
+func (wrapper *Wrapper) afterCommit(ctx context.Context, err error) {
+	for _, hook := range wrapper.hooks {
+		hook.AfterCommit(ctx, err)
+	}
+}
+
+func (wrapper *Wrapper) beforeRevert(ctx context.Context) context.Context {
+	for _, hook := range wrapper.hooks {
+		ctx = hook.BeforeRevert(ctx)
+	}
+	return ctx
+}
+
+func (wrapper *Wrapper) afterRevert(ctx context.Context, err error) {
+	for _, hook := range wrapper.hooks {
+		hook.AfterRevert(ctx, err)
+	}
+}
+
+// secretArgsKey is the context key NamedQuery/NamedExecute use to tell
+// LoggerHook which positional argument indices came from a field tagged
+// `sql:"col,secret"` and must be redacted before logging.
+type secretArgsKey struct{}
+
+func withSecretArgs(ctx context.Context, secret map[int]bool) context.Context {
+	if len(secret) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, secretArgsKey{}, secret)
+}
+
+func secretArgsFrom(ctx context.Context) map[int]bool {
+	secret, _ := ctx.Value(secretArgsKey{}).(map[int]bool)
+	return secret
+}
+
+// Logger is the subset of *log.Logger that LoggerHook needs, so callers can
+// plug in any structured logger that exposes a Printf-style method.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+type loggerHookStartKey struct{}
+
+// LoggerHook is a built-in Hook that writes each query, exec, and
+// transaction boundary to a Logger. Arguments bound by NamedQuery/
+// NamedExecute from a field tagged `sql:"col,secret"` are replaced with
+// "***" so credentials do not leak into logs.
+type LoggerHook struct {
+	Logger Logger
+}
+
+// NewLoggerHook returns a LoggerHook that writes to logger.
+func NewLoggerHook(logger Logger) *LoggerHook {
+	return &LoggerHook{Logger: logger}
+}
+
+func (hook *LoggerHook) BeforeQuery(ctx context.Context, sql string, args []interface{}) context.Context {
+	return context.WithValue(ctx, loggerHookStartKey{}, time.Now())
+}
+
+func (hook *LoggerHook) AfterQuery(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error) {
+	hook.log(ctx, "query", sql, args, rowsAffected, err)
+}
+
+func (hook *LoggerHook) BeforeExecute(ctx context.Context, sql string, args []interface{}) context.Context {
+	return context.WithValue(ctx, loggerHookStartKey{}, time.Now())
+}
+
+func (hook *LoggerHook) AfterExecute(ctx context.Context, sql string, args []interface{}, rowsAffected int64, err error) {
+	hook.log(ctx, "exec", sql, args, rowsAffected, err)
+}
+
+func (hook *LoggerHook) BeforeBegin(ctx context.Context) context.Context { return ctx }
+
+func (hook *LoggerHook) AfterBegin(ctx context.Context, err error) {
+	hook.Logger.Printf("godb: begin err=%v", err)
+}
+
+func (hook *LoggerHook) BeforeCommit(ctx context.Context) context.Context { return ctx }
+
+func (hook *LoggerHook) AfterCommit(ctx context.Context, err error) {
+	hook.Logger.Printf("godb: commit err=%v", err)
+}
+
+func (hook *LoggerHook) BeforeRevert(ctx context.Context) context.Context { return ctx }
+
+func (hook *LoggerHook) AfterRevert(ctx context.Context, err error) {
+	hook.Logger.Printf("godb: revert err=%v", err)
+}
+
+func (hook *LoggerHook) log(ctx context.Context, kind, sql string, args []interface{}, rowsAffected int64, err error) {
+	var duration time.Duration
+	if start, ok := ctx.Value(loggerHookStartKey{}).(time.Time); ok {
+		duration = time.Since(start)
+	}
+	hook.Logger.Printf("godb: %s sql=%q args=%v rows=%d duration=%s err=%v", kind, sql, hook.redact(ctx, args), rowsAffected, duration, err)
+}
+
+func (hook *LoggerHook) redact(ctx context.Context, args []interface{}) []interface{} {
+	secret := secretArgsFrom(ctx)
+	if len(secret) == 0 {
+		return args
+	}
+
+	redacted := make([]interface{}, len(args))
+	copy(redacted, args)
+	for i := range redacted {
+		if secret[i] {
+			redacted[i] = "***"
+		}
+	}
+	return redacted
+}
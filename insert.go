@@ -0,0 +1,183 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// defaultMaxParams bounds how many placeholders a single multi-row INSERT
+// built by InsertMany/InsertMap may contain before it is split into several
+// statements, unless overridden via Wrapper.MaxInsertParams. It matches the
+// lowest common driver limit in everyday use (MySQL allows up to 65535,
+// PostgreSQL up to 32767).
+const defaultMaxParams = 32767
+
+// InsertMany inserts rows into table in as few multi-row
+// `INSERT INTO table (...) VALUES (...), (...), ...` statements as possible,
+// built from the exported, `sql`-tagged fields of each element of rows
+// (every element must be the same struct type, or a pointer to one). Rows
+// run inside wrapper.Transaction if one is already open, or inside a
+// transaction opened and committed for the call otherwise. It returns the
+// total number of rows affected.
+func (wrapper *Wrapper) InsertMany(table string, rows []interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	columns, err := structColumns(reflect.TypeOf(rows[0]))
+	if err != nil {
+		return 0, err
+	}
+
+	maps := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		if maps[i], _, err = namedArgMap(row); err != nil {
+			return 0, err
+		}
+	}
+
+	return wrapper.insertRows(table, columns, maps)
+}
+
+// InsertMap is InsertMany for callers who already have their rows as
+// map[string]interface{} rather than tagged structs. Every map must share
+// the same set of keys as rows[0].
+func (wrapper *Wrapper) InsertMap(table string, rows []map[string]interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	columns := make([]string, 0, len(rows[0]))
+	for column := range rows[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return wrapper.insertRows(table, columns, rows)
+}
+
+func structColumns(t reflect.Type) ([]string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("godb: InsertMany: rows must be structs or pointers to structs, got %v", t.Kind())
+	}
+
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get(structTag); tag != "" {
+			column, _ := parseSQLTag(tag)
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+func (wrapper *Wrapper) insertRows(table string, columns []string, rows []map[string]interface{}) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("godb: InsertMany: %s rows have no columns to insert", table)
+	}
+
+	maxParams := wrapper.MaxInsertParams
+	if maxParams <= 0 {
+		maxParams = defaultMaxParams
+	}
+	if maxParams < len(columns) {
+		return 0, fmt.Errorf("godb: InsertMany: MaxInsertParams (%d) is smaller than the column count (%d)", maxParams, len(columns))
+	}
+	rowsPerChunk := maxParams / len(columns)
+
+	ownTransaction := wrapper.Transaction == nil
+	if ownTransaction {
+		if err := wrapper.Begin(); err != nil {
+			return 0, err
+		}
+	}
+
+	var total int64
+	for start := 0; start < len(rows); start += rowsPerChunk {
+		end := start + rowsPerChunk
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		statement, params, err := wrapper.insertStatement(table, columns, rows[start:end])
+		if err == nil {
+			err = wrapper.Execute(statement, params...)
+		}
+		if err != nil {
+			if ownTransaction {
+				wrapper.Revert()
+			}
+			return total, err
+		}
+
+		affected, err := wrapper.GetRowsAffected()
+		if err != nil {
+			if ownTransaction {
+				wrapper.Revert()
+			}
+			return total, err
+		}
+		total += int64(affected)
+	}
+
+	if ownTransaction {
+		if err := wrapper.Commit(); err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+func (wrapper *Wrapper) insertStatement(table string, columns []string, rows []map[string]interface{}) (string, []interface{}, error) {
+	bind := wrapper.bindType()
+	pos := 1
+
+	var statement strings.Builder
+	statement.WriteString("INSERT INTO ")
+	statement.WriteString(table)
+	statement.WriteString(" (")
+	statement.WriteString(strings.Join(columns, ", "))
+	statement.WriteString(") VALUES ")
+
+	params := make([]interface{}, 0, len(columns)*len(rows))
+	for i, row := range rows {
+		if i > 0 {
+			statement.WriteString(", ")
+		}
+		statement.WriteString("(")
+		for j, column := range columns {
+			if j > 0 {
+				statement.WriteString(", ")
+			}
+			value, ok := row[column]
+			if !ok {
+				return "", nil, fmt.Errorf("godb: InsertMany: row %d is missing column %q", i, column)
+			}
+			statement.WriteString(placeholder(bind, &pos))
+			params = append(params, value)
+		}
+		statement.WriteString(")")
+	}
+
+	return statement.String(), params, nil
+}
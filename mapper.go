@@ -0,0 +1,119 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structTag is the struct tag examined when mapping result columns onto
+// struct fields. It defaults to "sql" and can be overridden globally with
+// RegisterTag.
+var structTag = "sql"
+
+// RegisterTag overrides the struct tag used to map result columns onto
+// struct fields for every Wrapper. It defaults to "sql".
+func RegisterTag(name string) {
+	if name == "" {
+		return
+	}
+	mapperMu.Lock()
+	structTag = name
+	typeMappers = make(map[reflect.Type]*structMapper)
+	mapperMu.Unlock()
+}
+
+// fieldPlan describes how a single tagged struct field should be populated
+// from a scanned column, precomputed once per struct type instead of being
+// re-derived from reflect.StructTag on every row.
+type fieldPlan struct {
+	index   []int // field index path, see reflect.Value.FieldByIndex
+	column  string
+	kind    reflect.Kind
+	typ     reflect.Type
+	decoder typeDecoder // set when a decoder is registered for typ, see RegisterTypeDecoder
+}
+
+// structMapper is the precomputed plan for populating every tagged field of
+// a single struct type.
+type structMapper struct {
+	fields []fieldPlan
+}
+
+var (
+	mapperMu    sync.RWMutex
+	typeMappers = make(map[reflect.Type]*structMapper)
+)
+
+// mapperFor returns the cached structMapper for t, building and caching it on
+// first use.
+func mapperFor(t reflect.Type) *structMapper {
+	mapperMu.RLock()
+	m, ok := typeMappers[t]
+	mapperMu.RUnlock()
+	if ok {
+		return m
+	}
+
+	mapperMu.Lock()
+	defer mapperMu.Unlock()
+	if m, ok := typeMappers[t]; ok {
+		return m
+	}
+
+	m = buildStructMapper(t)
+	typeMappers[t] = m
+	return m
+}
+
+func buildStructMapper(t reflect.Type) *structMapper {
+	m := &structMapper{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(structTag)
+		if tag == "" {
+			continue
+		}
+		column, _ := parseSQLTag(tag)
+		decoder, _ := lookupTypeDecoder(field.Type)
+		m.fields = append(m.fields, fieldPlan{
+			index:   field.Index,
+			column:  column,
+			kind:    field.Type.Kind(),
+			typ:     field.Type,
+			decoder: decoder,
+		})
+	}
+	return m
+}
+
+// parseSQLTag splits a `sql:"column,opt1,opt2"` tag into its column name and
+// any trailing options. The only option currently recognised is "secret",
+// used by NamedQuery/NamedExecute and LoggerHook to redact a value from logs.
+func parseSQLTag(tag string) (column string, opts []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+func hasTagOption(opts []string, name string) bool {
+	for _, opt := range opts {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	geo "github.com/paulmach/go.geo"
+)
+
+type mapperTestRow struct {
+	ID        int        `sql:"id"`
+	Name      string     `sql:"name"`
+	Active    bool       `sql:"active"`
+	Score     float64    `sql:"score"`
+	CreatedAt *time.Time `sql:"created_at"`
+	Location  *geo.Point `sql:"location"`
+}
+
+// newScanResult builds a ScanResult as if the driver had returned values,
+// without going through a real *sql.Rows.
+func newScanResult(values map[string]interface{}) *ScanResult {
+	fields := make([]string, 0, len(values))
+	for k := range values {
+		fields = append(fields, k)
+	}
+
+	var sr ScanResult
+	sr.PutFields(fields)
+	for _, k := range fields {
+		sr.FieldArr[sr.MapFieldToID[k]] = values[k]
+	}
+	return &sr
+}
+
+func TestUnmarshalIntoBasicFields(t *testing.T) {
+	created, err := time.Parse(time.RFC3339, "2024-01-02T15:04:05Z")
+	if err != nil {
+		t.Fatalf("time.Parse: %v", err)
+	}
+
+	sr := newScanResult(map[string]interface{}{
+		"id":         int64(7),
+		"name":       "alice",
+		"active":     int64(1),
+		"score":      "3.5",
+		"created_at": "2024-01-02T15:04:05Z",
+		"location":   nil,
+	})
+
+	var row mapperTestRow
+	if err := unmarshalInto(sr, reflect.ValueOf(&row).Elem()); err != nil {
+		t.Fatalf("unmarshalInto: %v", err)
+	}
+
+	if row.ID != 7 {
+		t.Errorf("ID = %d, want 7", row.ID)
+	}
+	if row.Name != "alice" {
+		t.Errorf("Name = %q, want %q", row.Name, "alice")
+	}
+	if !row.Active {
+		t.Errorf("Active = false, want true")
+	}
+	if row.Score != 3.5 {
+		t.Errorf("Score = %v, want 3.5", row.Score)
+	}
+	if row.CreatedAt == nil || !row.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", row.CreatedAt, created)
+	}
+	if row.Location == nil {
+		t.Errorf("Location = nil, want zero-value *geo.Point for a NULL column")
+	}
+}
+
+func TestUnmarshalIntoDecodeError(t *testing.T) {
+	type decoderTestRow struct {
+		Location *geo.Point `sql:"location"`
+	}
+
+	sr := newScanResult(map[string]interface{}{
+		"location": []uint8{0x01, 0x02}, // too short to be a real WKB value
+	})
+
+	var row decoderTestRow
+	err := unmarshalInto(sr, reflect.ValueOf(&row).Elem())
+	if err == nil {
+		t.Fatal("unmarshalInto: expected an error for a malformed geo column, got nil")
+	}
+
+	var decodeErr *DecodeError
+	if !okAsDecodeError(err, &decodeErr) {
+		t.Fatalf("unmarshalInto: error = %v (%T), want *DecodeError", err, err)
+	}
+	if decodeErr.Column != "location" {
+		t.Errorf("DecodeError.Column = %q, want %q", decodeErr.Column, "location")
+	}
+}
+
+func okAsDecodeError(err error, target **DecodeError) bool {
+	de, ok := err.(*DecodeError)
+	if ok {
+		*target = de
+	}
+	return ok
+}
+
+func TestMapperForCachesByType(t *testing.T) {
+	t1 := mapperFor(reflect.TypeOf(mapperTestRow{}))
+	t2 := mapperFor(reflect.TypeOf(mapperTestRow{}))
+	if t1 != t2 {
+		t.Error("mapperFor returned a different *structMapper for the same type, expected the cached one")
+	}
+	if len(t1.fields) != 6 {
+		t.Errorf("len(fields) = %d, want 6", len(t1.fields))
+	}
+}
+
+func BenchmarkUnmarshalIntoWideRow(b *testing.B) {
+	sr := newScanResult(map[string]interface{}{
+		"id":         int64(7),
+		"name":       "alice",
+		"active":     int64(1),
+		"score":      "3.5",
+		"created_at": "2024-01-02T15:04:05Z",
+		"location":   nil,
+	})
+
+	var row mapperTestRow
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := unmarshalInto(sr, reflect.ValueOf(&row).Elem()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// BindType identifies the positional placeholder syntax a driver expects in
+// place of a rebound `:name` parameter.
+type BindType int
+
+// Supported bind types.
+const (
+	// Question is the `?` placeholder used by MySQL and SQLite.
+	Question BindType = iota
+	// Dollar is the `$1` placeholder used by PostgreSQL.
+	Dollar
+	// At is the `@p1` placeholder used by SQL Server.
+	At
+)
+
+// bindType guesses the wrapper's BindType from its underlying driver's type
+// name, since database/sql has no direct way to ask a *sql.DB what
+// placeholder syntax it expects.
+func (wrapper *Wrapper) bindType() BindType {
+	if wrapper.Connection == nil {
+		return Question
+	}
+	switch fmt.Sprintf("%T", wrapper.Connection.Driver()) {
+	case "*pq.Driver", "*stdlib.Driver", "*pgx.Driver":
+		return Dollar
+	case "*mssql.Driver", "*sqlserver.Driver":
+		return At
+	default:
+		return Question
+	}
+}
+
+// placeholder renders the next positional placeholder for bind, advancing
+// pos for the bind types that number their placeholders.
+func placeholder(bind BindType, pos *int) string {
+	switch bind {
+	case Dollar:
+		s := fmt.Sprintf("$%d", *pos)
+		*pos++
+		return s
+	case At:
+		s := fmt.Sprintf("@p%d", *pos)
+		*pos++
+		return s
+	default:
+		return "?"
+	}
+}
+
+// bindNamed rewrites a query containing `:name` placeholders into the
+// wrapper's driver-native positional syntax, returning the rewritten query,
+// the arguments in the order the placeholders now appear, and the set of
+// resulting positional indices that came from a field tagged
+// `sql:"col,secret"` (for LoggerHook redaction). arg must be a
+// map[string]interface{} or a struct whose exported fields carry `sql:"col"`
+// tags. A slice-typed value (other than []byte) is expanded into one
+// placeholder per element, e.g. `IN (:ids)` with ids []int{1,2,3} becomes
+// `IN (?, ?, ?)`.
+func (wrapper *Wrapper) bindNamed(query string, arg interface{}) (string, []interface{}, map[int]bool, error) {
+	values, secretCols, err := namedArgMap(arg)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	bind := wrapper.bindType()
+
+	var out strings.Builder
+	var args []interface{}
+	secretArgs := make(map[int]bool)
+	pos := 1
+
+	runes := []rune(query)
+	n := len(runes)
+	var inQuote rune
+
+	for i := 0; i < n; i++ {
+		c := runes[i]
+
+		if inQuote != 0 {
+			out.WriteRune(c)
+			if c == inQuote {
+				inQuote = 0
+			}
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+			inQuote = c
+			out.WriteRune(c)
+			continue
+		}
+
+		if c == ':' && i+1 < n && runes[i+1] == ':' {
+			// Postgres type cast (`col::text`), not a named placeholder.
+			out.WriteString("::")
+			i++
+			continue
+		}
+
+		if c == ':' && i+1 < n && isNameStart(runes[i+1]) {
+			j := i + 1
+			for j < n && isNameChar(runes[j]) {
+				j++
+			}
+			name := string(runes[i+1 : j])
+
+			value, ok := values[name]
+			if !ok {
+				return "", nil, nil, fmt.Errorf("godb: NamedQuery: missing named parameter %q", name)
+			}
+
+			rv := reflect.ValueOf(value)
+			if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+				length := rv.Len()
+				if length == 0 {
+					return "", nil, nil, fmt.Errorf("godb: NamedQuery: named parameter %q is an empty slice", name)
+				}
+				for k := 0; k < length; k++ {
+					if k > 0 {
+						out.WriteString(", ")
+					}
+					out.WriteString(placeholder(bind, &pos))
+					args = append(args, rv.Index(k).Interface())
+					if secretCols[name] {
+						secretArgs[len(args)-1] = true
+					}
+				}
+			} else {
+				out.WriteString(placeholder(bind, &pos))
+				args = append(args, value)
+				if secretCols[name] {
+					secretArgs[len(args)-1] = true
+				}
+			}
+
+			i = j - 1
+			continue
+		}
+
+		out.WriteRune(c)
+	}
+
+	return out.String(), args, secretArgs, nil
+}
+
+func isNameStart(c rune) bool {
+	return c == '_' || unicode.IsLetter(c)
+}
+
+func isNameChar(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// namedArgMap resolves a NamedQuery/NamedExecute/InsertMany argument into a
+// column-name-to-value map, along with the set of column names whose field
+// carried the `secret` tag option.
+func namedArgMap(arg interface{}) (map[string]interface{}, map[string]bool, error) {
+	if arg == nil {
+		return nil, nil, errors.New("godb: NamedQuery: argument must not be nil")
+	}
+
+	if m, ok := arg.(map[string]interface{}); ok {
+		return m, nil, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil, errors.New("godb: NamedQuery: argument must not be a nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("godb: NamedQuery: argument must be a map[string]interface{} or a struct, got %v", v.Kind())
+	}
+
+	t := v.Type()
+	m := make(map[string]interface{}, v.NumField())
+	secret := make(map[string]bool)
+	for i := 0; i < v.NumField(); i++ {
+		tag := t.Field(i).Tag.Get(structTag)
+		if tag == "" {
+			continue
+		}
+		column, opts := parseSQLTag(tag)
+		m[column] = v.Field(i).Interface()
+		if hasTagOption(opts, "secret") {
+			secret[column] = true
+		}
+	}
+
+	return m, secret, nil
+}
+
+// NamedQuery is the Query equivalent of sqlx-style named parameters: the
+// query may contain `:name` placeholders, which are resolved against arg (a
+// map[string]interface{} or a struct tagged with `sql:"col"`) and rewritten
+// to the driver's positional placeholder syntax before being run. Any field
+// also tagged `secret` is redacted if a LoggerHook is attached.
+func (wrapper *Wrapper) NamedQuery(queryStatement string, arg interface{}) error {
+	statement, params, secretArgs, err := wrapper.bindNamed(queryStatement, arg)
+	if err != nil {
+		return err
+	}
+	return wrapper.QueryContext(withSecretArgs(context.Background(), secretArgs), statement, params...)
+}
+
+// NamedQueryOne is the NamedQuery equivalent of QueryOne.
+func (wrapper *Wrapper) NamedQueryOne(queryStatement string, arg interface{}) (interface{}, error) {
+	statement, params, secretArgs, err := wrapper.bindNamed(queryStatement, arg)
+	if err != nil {
+		return nil, err
+	}
+	return wrapper.QueryOneContext(withSecretArgs(context.Background(), secretArgs), statement, params...)
+}
+
+// NamedExecute is the NamedQuery equivalent of Execute.
+func (wrapper *Wrapper) NamedExecute(statementString string, arg interface{}) error {
+	statement, params, secretArgs, err := wrapper.bindNamed(statementString, arg)
+	if err != nil {
+		return err
+	}
+	return wrapper.ExecuteContext(withSecretArgs(context.Background(), secretArgs), statement, params...)
+}
+
+// NamedPrepare is the NamedQuery equivalent of Prepare. Since a prepared
+// statement has no arguments of its own yet, it returns the bound arguments
+// alongside the statement, in the order expected by Stmt.Exec/Stmt.Query.
+func (wrapper *Wrapper) NamedPrepare(statementString string, arg interface{}) (*sql.Stmt, []interface{}, error) {
+	statement, params, _, err := wrapper.bindNamed(statementString, arg)
+	if err != nil {
+		return nil, nil, err
+	}
+	stmt, err := wrapper.Prepare(statement)
+	return stmt, params, err
+}
@@ -0,0 +1,199 @@
+package godb
+
+// Copyright 2019 Just Another Organization
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// fieldSource is satisfied by anything that can answer Get*/getVal for a
+// single row. *Wrapper answers for its current ScanResult; *StreamCursor
+// answers for whichever row it is parked on.
+type fieldSource interface {
+	GetInt(key string) int
+	GetString(key string) string
+	GetBool(key string) bool
+	getVal(key string) (interface{}, bool)
+}
+
+// StreamCursor iterates over a query's results one row at a time, keeping
+// exactly one ScanResult in memory instead of buffering the whole result set
+// the way Wrapper.Query does.
+type StreamCursor struct {
+	statement *sql.Stmt
+	rows      *sql.Rows
+	fields    []string
+	current   *ScanResult
+	err       error
+}
+
+// QueryStream runs queryStatement and returns a cursor over its rows. The
+// caller must call Close when done with the cursor, typically via defer.
+func (wrapper *Wrapper) QueryStream(queryStatement string, params ...interface{}) (*StreamCursor, error) {
+	return wrapper.QueryStreamContext(context.Background(), queryStatement, params...)
+}
+
+// QueryStreamContext is the context-aware equivalent of QueryStream.
+func (wrapper *Wrapper) QueryStreamContext(ctx context.Context, queryStatement string, params ...interface{}) (*StreamCursor, error) {
+	statement, err := wrapper.PrepareContext(ctx, queryStatement)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := statement.QueryContext(ctx, params...)
+	if err != nil {
+		statement.Close()
+		return nil, err
+	}
+
+	fields, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		statement.Close()
+		return nil, err
+	}
+
+	return &StreamCursor{statement: statement, rows: rows, fields: fields}, nil
+}
+
+// Next advances the cursor to the next row, releasing whatever row it held
+// before. It returns false once the result set is exhausted or a scan error
+// occurs; call Err to tell the two apart.
+func (cursor *StreamCursor) Next() bool {
+	if !cursor.rows.Next() {
+		cursor.current = nil
+		cursor.err = cursor.rows.Err()
+		return false
+	}
+
+	var result ScanResult
+	result.PutFields(cursor.fields)
+	if err := cursor.rows.Scan(result.GetFieldPtrArr()...); err != nil {
+		cursor.current = nil
+		cursor.err = err
+		return false
+	}
+
+	cursor.current = &result
+	return true
+}
+
+// Err returns the error, if any, that stopped Next.
+func (cursor *StreamCursor) Err() error {
+	return cursor.err
+}
+
+// Close releases the underlying rows and statement. It is safe to call more
+// than once.
+func (cursor *StreamCursor) Close() error {
+	var rowsErr, statementErr error
+	if cursor.rows != nil {
+		rowsErr = cursor.rows.Close()
+	}
+	if cursor.statement != nil {
+		statementErr = cursor.statement.Close()
+	}
+	if rowsErr != nil {
+		return rowsErr
+	}
+	return statementErr
+}
+
+func (cursor *StreamCursor) currentOrZero() *ScanResult {
+	if cursor.current == nil {
+		return &ScanResult{}
+	}
+	return cursor.current
+}
+
+//GetInt returns an int for the specified key in the current row.
+func (cursor *StreamCursor) GetInt(key string) int {
+	return cursor.currentOrZero().GetInt(key)
+}
+
+//CheckInt ...
+func (cursor *StreamCursor) CheckInt(key string) (int, bool) {
+	return cursor.currentOrZero().CheckInt(key)
+}
+
+//GetString returns a string for the specified key in the current row.
+func (cursor *StreamCursor) GetString(key string) string {
+	return cursor.currentOrZero().GetString(key)
+}
+
+//CheckString ...
+func (cursor *StreamCursor) CheckString(key string) (string, bool) {
+	return cursor.currentOrZero().CheckString(key)
+}
+
+//GetBool returns a bool for the specified key in the current row.
+func (cursor *StreamCursor) GetBool(key string) bool {
+	return cursor.currentOrZero().GetBool(key)
+}
+
+//CheckBool ...
+func (cursor *StreamCursor) CheckBool(key string) (bool, bool) {
+	return cursor.currentOrZero().CheckBool(key)
+}
+
+// GetInterface ...
+func (cursor *StreamCursor) GetInterface(key string) interface{} {
+	return cursor.currentOrZero().GetInterface(key)
+}
+
+func (cursor *StreamCursor) getVal(key string) (interface{}, bool) {
+	if cursor.current == nil {
+		return nil, false
+	}
+	return cursor.current.getVal(key)
+}
+
+// Unmarshal populates i (a pointer to a struct) from the current row, using
+// the same tag-driven mapping as Wrapper.Unmarshal.
+func (cursor *StreamCursor) Unmarshal(i interface{}) error {
+	return unmarshalInto(cursor, reflect.ValueOf(i).Elem())
+}
+
+// StreamUnwrap runs queryStatement and, for each row, unmarshals it into dst
+// (a pointer to a struct) and invokes fn, so a caller can process an
+// arbitrarily large result set in bounded memory instead of calling Unwrap.
+func (wrapper *Wrapper) StreamUnwrap(queryStatement string, dst interface{}, fn func(interface{}) error, params ...interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return errors.New("Wrapper.StreamUnwrap: dst must be a pointer to a struct")
+	}
+
+	cursor, err := wrapper.QueryStream(queryStatement, params...)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	elem := value.Elem()
+	for cursor.Next() {
+		elem.Set(reflect.Zero(elem.Type()))
+		if err := unmarshalInto(cursor, elem); err != nil {
+			return err
+		}
+		if err := fn(dst); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -15,6 +15,7 @@ package godb
 // limitations under the License.
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -47,7 +48,19 @@ type Wrapper struct {
 	CurrentScan int //Should start at -1
 	Connection  *sql.DB
 	Transaction *sql.Tx
-	lastResult  sql.Result
+	// MaxInsertParams bounds how many placeholders InsertMany/InsertMap pack
+	// into a single multi-row INSERT before splitting into more statements.
+	// Zero means defaultMaxParams.
+	MaxInsertParams int
+	lastResult      sql.Result
+	hooks           []Hook
+}
+
+// AddHook registers hook to be notified around every query, exec, and
+// transaction boundary this Wrapper runs, except for queries run through
+// QueryStream, QueryStreamContext, or StreamUnwrap.
+func (wrapper *Wrapper) AddHook(hook Hook) {
+	wrapper.hooks = append(wrapper.hooks, hook)
 }
 
 // RowCount returns the amount of scan results.
@@ -59,27 +72,26 @@ func (wrapper *Wrapper) RowCount() int {
 }
 
 // Begin starts the transaction
-func (wrapper *Wrapper) Begin() (err error) {
-	wrapper.Transaction, err = wrapper.Connection.Begin()
-	return
+func (wrapper *Wrapper) Begin() error {
+	return wrapper.BeginTx(context.Background(), nil)
 }
 
 // Commit commits the transaction
 func (wrapper *Wrapper) Commit() error {
-	if err := wrapper.Transaction.Commit(); err != nil {
-		return err
-	}
-	wrapper.Transaction = nil
-	return nil
+	return wrapper.CommitContext(context.Background())
 }
 
 // Revert rolls back the transaction
 func (wrapper *Wrapper) Revert() error {
-	if err := wrapper.Transaction.Rollback(); err != nil {
-		return err
+	ctx := wrapper.beforeRevert(context.Background())
+
+	err := wrapper.Transaction.Rollback()
+	if err == nil {
+		wrapper.Transaction = nil
 	}
-	wrapper.Transaction = nil
-	return nil
+
+	wrapper.afterRevert(ctx, err)
+	return err
 }
 
 // Next is a dirty attempt at replicating scan.Next
@@ -90,29 +102,12 @@ func (wrapper *Wrapper) Next() bool {
 
 // Prepare prepares the statement.
 func (wrapper *Wrapper) Prepare(statement string) (*sql.Stmt, error) {
-	if wrapper.Transaction != nil {
-		return wrapper.Transaction.Prepare(statement)
-	}
-	return wrapper.Connection.Prepare(statement)
+	return wrapper.PrepareContext(context.Background(), statement)
 }
 
 // Execute executes the statement with the params, and returns last inserted id, and the rows affected.
-// TODO Remove TryToClose. Return error instead.
 func (wrapper *Wrapper) Execute(statementString string, params ...interface{}) error {
-	statement, err := wrapper.Prepare(statementString)
-	if err != nil {
-		return err
-	}
-	defer statement.Close()
-
-	results, err := statement.Exec(params...)
-	if err != nil {
-		return err
-	}
-
-	wrapper.lastResult = results
-
-	return nil
+	return wrapper.ExecuteContext(context.Background(), statementString, params...)
 }
 
 // GetLastInsertedID will return the last inserted ID from the last executed SQL
@@ -147,73 +142,13 @@ func (wrapper *Wrapper) Wrap(db *sql.DB) {
 }
 
 //Query queries the query string, with whatever params given.
-// TODO Remove TryToClose. Return error instead.
 func (wrapper *Wrapper) Query(queryStatement string, params ...interface{}) error {
-	wrapper.clear()
-	statement, err := wrapper.Prepare(queryStatement)
-	if err != nil {
-		return err
-	}
-	defer statement.Close()
-
-	results, err := statement.Query(params...)
-	if err != nil {
-		// TODO Debug
-		return err
-	}
-	defer statement.Close()
-
-	var fArr []string
-	if fArr, err = results.Columns(); err != nil {
-		return err
-	}
-
-	for count := 0; results.Next(); count++ {
-		var result ScanResult
-		result.PutFields(fArr)
-		if err = results.Scan(result.GetFieldPtrArr()...); err != nil {
-			return err
-		}
-		wrapper.ScanResults = append(wrapper.ScanResults, &result)
-	}
-
-	return nil
+	return wrapper.QueryContext(context.Background(), queryStatement, params...)
 }
 
 //QueryOne queries the query string, with whatever params given, gives back one value as an interface.
-// TODO Remove TryToClose. Return error instead.
-func (wrapper *Wrapper) QueryOne(queryStatement string, params ...interface{}) (i interface{}, err error) {
-	wrapper.clear()
-	statement, err := wrapper.Prepare(queryStatement)
-	if err != nil {
-		return
-	}
-	defer statement.Close()
-
-	results, err := statement.Query(params...)
-	if err != nil {
-		return
-	}
-	defer statement.Close()
-
-	var fArr []string
-	if fArr, err = results.Columns(); err != nil || !results.Next() {
-		return
-	}
-	if len(fArr) > 1 {
-		err = errors.New("May only return one value with this function")
-		return
-	}
-
-	var sr ScanResult
-	sr.PutFields(fArr)
-	if err = results.Scan(sr.GetFieldPtrArr()...); err != nil {
-		return
-	}
-
-	i = sr.GetInterface(fArr[0])
-
-	return
+func (wrapper *Wrapper) QueryOne(queryStatement string, params ...interface{}) (interface{}, error) {
+	return wrapper.QueryOneContext(context.Background(), queryStatement, params...)
 }
 
 func (wrapper *Wrapper) getVal(key string) (interface{}, bool) {
@@ -306,121 +241,66 @@ func (wrapper *Wrapper) Unmarshal(i interface{}) error {
 }
 
 func (wrapper *Wrapper) unmarshal(v reflect.Value) error {
-	t := v.Type()
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		tag := t.Field(i).Tag.Get("sql")
-		if tag != "" {
-			switch field.Kind() {
-			case reflect.Int:
-				ptr := field.Addr().Interface().(*int)
-				*ptr = wrapper.GetInt(tag)
-			// case reflect.Int64:
-			// 	ptr := field.Addr().Interface().(*int64)
-			// 	*ptr = wrapper.GetInt64(tag)
-			case reflect.String:
-				ptr := field.Addr().Interface().(*string)
-				*ptr = wrapper.GetString(tag)
-			case reflect.Bool:
-				ptr := field.Addr().Interface().(*bool)
-				*ptr = wrapper.GetBool(tag)
-			case reflect.Float64:
-				ptr := field.Addr().Interface().(*float64)
-				*ptr, _ = strconv.ParseFloat(wrapper.GetString(tag), 64)
-
-				// Magic Reflection
-			case reflect.Slice, reflect.Array:
-				i := field.Interface()
-				if err := json.Unmarshal([]byte(wrapper.GetString(tag)), &i); err != nil {
-					break
-				}
-				a2v := reflect.ValueOf(i)
-				if !a2v.IsValid() {
-					break
-				}
-				a2vLen := a2v.Len()
-				if a2vLen > 0 {
-					field.Set(reflect.MakeSlice(field.Type(), a2vLen, a2vLen))
-					for i := 0; i < a2vLen; i++ {
-						if a2v.Index(i).Elem().Type().ConvertibleTo(field.Type().Elem()) {
-							field.Index(i).Set(a2v.Index(i).Elem().Convert(field.Type().Elem()))
-						}
-					}
-				}
-				// End Magic Reflection
-
-			case reflect.Struct, reflect.Ptr:
-				switch field.Type() {
-				case timePType:
-					ptr := field.Addr().Interface().(**time.Time)
-					*ptr = stringToTimePtr(wrapper.GetString(tag))
-
-					// Magic Geo Points....
-				case geoPoint:
-					val, ok := wrapper.getVal(tag)
-					if ok {
-						var byts []byte
-						// Uh since this is so unsafe, I decided to wrap it just incase...
-						// Reasons why it's unsafe:
-						// - Assuming type is []uint8
-						// - Assuming data type is WKT
-						// - Assuming I know what the type is.
-						func() {
-							defer func() {
-								if err := recover(); err != nil {
-								}
-							}()
-
-							byts = make([]byte, len(val.([]uint8)))
-							for i, v := range val.([]uint8) {
-								byts[i] = byte(v)
-							}
-							if len(byts) > 4 {
-
-								// ¯\_(ツ)_/¯
-								byts = byts[4:]
-							}
-
-						}()
-						// Lets pretend, that byts is correct and not think about it kkthx.
-						ptr := field.Addr().Interface().(**geo.Point)
-						*ptr = geo.NewPointFromWKB(byts)
-					}
-
-				case geoPath:
-					val, ok := wrapper.getVal(tag)
-					if ok {
-						var byts []byte
-						// Uh since this is so unsafe, I decided to wrap it just incase...
-						// Reasons why it's unsafe:
-						// - Assuming type is []uint8
-						// - Assuming data type is WKT
-						// - Assuming I know what the type is.
-						func() {
-							defer func() {
-								if err := recover(); err != nil {
-								}
-							}()
-
-							byts = make([]byte, len(val.([]uint8)))
-							for i, v := range val.([]uint8) {
-								byts[i] = byte(v)
-							}
-							if len(byts) > 4 {
-
-								// ¯\_(ツ)_/¯
-								byts = byts[4:]
-							}
-
-						}()
-						// Lets pretend, that byts is correct and not think about it kkthx.
-						ptr := field.Addr().Interface().(**geo.Path)
-						*ptr = geo.NewPathFromWKB(byts)
+	return unmarshalInto(wrapper, v)
+}
+
+// unmarshalInto populates v (a struct value) from src, the current row of
+// either a *Wrapper or a *StreamCursor.
+func unmarshalInto(src fieldSource, v reflect.Value) error {
+	mapper := mapperFor(v.Type())
+	for _, plan := range mapper.fields {
+		field := v.FieldByIndex(plan.index)
+		switch plan.kind {
+		case reflect.Int:
+			ptr := field.Addr().Interface().(*int)
+			*ptr = src.GetInt(plan.column)
+		// case reflect.Int64:
+		// 	ptr := field.Addr().Interface().(*int64)
+		// 	*ptr = src.GetInt64(plan.column)
+		case reflect.String:
+			ptr := field.Addr().Interface().(*string)
+			*ptr = src.GetString(plan.column)
+		case reflect.Bool:
+			ptr := field.Addr().Interface().(*bool)
+			*ptr = src.GetBool(plan.column)
+		case reflect.Float64:
+			ptr := field.Addr().Interface().(*float64)
+			*ptr, _ = strconv.ParseFloat(src.GetString(plan.column), 64)
+
+			// Magic Reflection
+		case reflect.Slice, reflect.Array:
+			i := field.Interface()
+			if err := json.Unmarshal([]byte(src.GetString(plan.column)), &i); err != nil {
+				break
+			}
+			a2v := reflect.ValueOf(i)
+			if !a2v.IsValid() {
+				break
+			}
+			a2vLen := a2v.Len()
+			if a2vLen > 0 {
+				field.Set(reflect.MakeSlice(field.Type(), a2vLen, a2vLen))
+				for i := 0; i < a2vLen; i++ {
+					if a2v.Index(i).Elem().Type().ConvertibleTo(field.Type().Elem()) {
+						field.Index(i).Set(a2v.Index(i).Elem().Convert(field.Type().Elem()))
 					}
-
 				}
+			}
+			// End Magic Reflection
 
+		case reflect.Struct, reflect.Ptr:
+			if plan.decoder == nil {
+				break
+			}
+			val, ok := src.getVal(plan.column)
+			if !ok {
+				break
+			}
+			decoded, err := plan.decoder(val)
+			if err != nil {
+				return &DecodeError{Column: plan.column, Type: plan.typ, Err: err}
 			}
+			field.Set(reflect.ValueOf(decoded))
 		}
 	}
 	return nil